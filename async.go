@@ -0,0 +1,85 @@
+package log
+
+import (
+	"io"
+	"sync"
+)
+
+// AsyncWriter wraps an io.Writer with a bounded channel so a slow
+// underlying writer (a spinning disk, a remote sink) never blocks
+// Event.Send. Once the queue is full, the oldest queued write is
+// dropped to make room for the newest one.
+type AsyncWriter struct {
+	Writer    io.Writer
+	QueueSize int
+
+	once   sync.Once
+	mu     sync.Mutex
+	ch     chan []byte
+	done   chan struct{}
+	closed bool
+}
+
+func (w *AsyncWriter) init() {
+	size := w.QueueSize
+	if size <= 0 {
+		size = 4096
+	}
+	w.ch = make(chan []byte, size)
+	w.done = make(chan struct{})
+	go w.run()
+}
+
+func (w *AsyncWriter) run() {
+	for p := range w.ch {
+		w.Writer.Write(p)
+	}
+	close(w.done)
+}
+
+// Write implements io.Writer. It never blocks: if the queue is full, the
+// oldest pending write is dropped to make room for this one. The drain
+// and the retry happen under mu so concurrent writers can't race each
+// other into refilling the slot just freed, which would otherwise drop
+// this (newest) write instead of the oldest one. mu also guards closed,
+// so a Write racing a Close either completes before the channel closes
+// or observes closed and bails out, never sending on a closed channel.
+func (w *AsyncWriter) Write(p []byte) (int, error) {
+	w.once.Do(w.init)
+	buf := append([]byte(nil), p...)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return 0, io.ErrClosedPipe
+	}
+	select {
+	case w.ch <- buf:
+		return len(p), nil
+	default:
+	}
+	select {
+	case <-w.ch:
+	default:
+	}
+	select {
+	case w.ch <- buf:
+	default:
+	}
+	return len(p), nil
+}
+
+// Close drains the queue to Writer and closes it if it implements
+// io.Closer.
+func (w *AsyncWriter) Close() error {
+	w.once.Do(w.init)
+	w.mu.Lock()
+	w.closed = true
+	close(w.ch)
+	w.mu.Unlock()
+	<-w.done
+	if c, ok := w.Writer.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
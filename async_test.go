@@ -0,0 +1,105 @@
+package log
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+)
+
+type blockingWriter struct {
+	release chan struct{}
+	mu      sync.Mutex
+	writes  [][]byte
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	<-w.release
+	w.mu.Lock()
+	w.writes = append(w.writes, append([]byte(nil), p...))
+	w.mu.Unlock()
+	return len(p), nil
+}
+
+// TestAsyncWriterDropsOldestKeepsNewest fills the queue past capacity
+// while the underlying Writer is blocked, then proves the newest write
+// survived and the run loop never panicked or dropped a write on the
+// floor.
+func TestAsyncWriterDropsOldestKeepsNewest(t *testing.T) {
+	bw := &blockingWriter{release: make(chan struct{})}
+	w := &AsyncWriter{Writer: bw, QueueSize: 1}
+
+	w.Write([]byte("a"))
+	// Wait until the run loop has taken "a" and is blocked writing it, so
+	// the queue's contents below do not depend on goroutine scheduling.
+	for len(w.ch) != 0 {
+		runtime.Gosched()
+	}
+	w.Write([]byte("b"))
+	w.Write([]byte("c")) // queue full at "b": drops "b", keeps "c"
+
+	close(bw.release)
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	bw.mu.Lock()
+	defer bw.mu.Unlock()
+	if len(bw.writes) != 2 {
+		t.Fatalf("got %d writes, want 2: %q", len(bw.writes), bw.writes)
+	}
+	if string(bw.writes[len(bw.writes)-1]) != "c" {
+		t.Errorf("last write = %q, want %q (newest must survive)", bw.writes[len(bw.writes)-1], "c")
+	}
+}
+
+// TestAsyncWriterConcurrentWritesRace exercises Write from many goroutines
+// against a full queue at once; run with -race to confirm the drain+retry
+// critical section in Write is properly serialized.
+func TestAsyncWriterConcurrentWritesRace(t *testing.T) {
+	bw := &blockingWriter{release: make(chan struct{})}
+	close(bw.release)
+	w := &AsyncWriter{Writer: bw, QueueSize: 1}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 32; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				w.Write([]byte{byte(g)})
+			}
+		}(g)
+	}
+	wg.Wait()
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+}
+
+// TestAsyncWriterCloseDuringConcurrentWrite races Write against Close
+// repeatedly; Close must take the same mutex Write's drop-oldest section
+// uses before closing the channel, otherwise a Write still inside that
+// section can hit "send on closed channel".
+func TestAsyncWriterCloseDuringConcurrentWrite(t *testing.T) {
+	for i := 0; i < 200; i++ {
+		bw := &blockingWriter{release: make(chan struct{})}
+		close(bw.release)
+		w := &AsyncWriter{Writer: bw, QueueSize: 1}
+
+		var wg sync.WaitGroup
+		for g := 0; g < 8; g++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() {
+					if r := recover(); r != nil {
+						t.Errorf("Write panicked: %v", r)
+					}
+				}()
+				w.Write([]byte("x"))
+			}()
+		}
+		w.Close()
+		wg.Wait()
+	}
+}
@@ -0,0 +1,384 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Format selects the wire encoding Logger and Event use to serialize
+// fields. The zero value is FormatJSON, so existing Logger values keep
+// emitting JSON without any changes.
+type Format int8
+
+const (
+	// FormatJSON renders each Event as a single line of JSON. This is the
+	// default and matches the package's historical behavior.
+	FormatJSON Format = iota
+	// FormatCBOR renders each Event as a single RFC 7049 CBOR map. It is
+	// smaller on the wire than JSON and lets Bytes/Interface emit native
+	// CBOR byte strings instead of a base64 or escaped-JSON detour.
+	FormatCBOR
+)
+
+// CBOR major types, see https://www.rfc-editor.org/rfc/rfc7049#section-2.1
+const (
+	cborMajorUint   = 0
+	cborMajorNegInt = 1
+	cborMajorBytes  = 2
+	cborMajorText   = 3
+	cborMajorArray  = 4
+	cborMajorMap    = 5
+	cborMajorSimple = 7
+)
+
+const (
+	cborBreak   = 0xff
+	cborMapOpen = byte(cborMajorMap<<5) | 31
+	cborFalse   = byte(cborMajorSimple<<5) | 20
+	cborTrue    = byte(cborMajorSimple<<5) | 21
+	cborNull    = byte(cborMajorSimple<<5) | 22
+	cborFloat64 = byte(cborMajorSimple<<5) | 27
+)
+
+func appendCBORHead(buf []byte, major byte, n uint64) []byte {
+	m := major << 5
+	switch {
+	case n < 24:
+		return append(buf, m|byte(n))
+	case n <= 0xff:
+		return append(buf, m|24, byte(n))
+	case n <= 0xffff:
+		return append(buf, m|25, byte(n>>8), byte(n))
+	case n <= 0xffffffff:
+		return append(buf, m|26, byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+	default:
+		return append(buf, m|27, byte(n>>56), byte(n>>48), byte(n>>40), byte(n>>32), byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+	}
+}
+
+func (e *Event) cborKey(key string) {
+	e.buf = appendCBORHead(e.buf, cborMajorText, uint64(len(key)))
+	e.buf = append(e.buf, key...)
+}
+
+func (e *Event) cborStr(s string) {
+	e.buf = appendCBORHead(e.buf, cborMajorText, uint64(len(s)))
+	e.buf = append(e.buf, s...)
+}
+
+func (e *Event) cborBytes(b []byte) {
+	e.buf = appendCBORHead(e.buf, cborMajorBytes, uint64(len(b)))
+	e.buf = append(e.buf, b...)
+}
+
+func (e *Event) cborInt64(i int64) {
+	if i >= 0 {
+		e.buf = appendCBORHead(e.buf, cborMajorUint, uint64(i))
+		return
+	}
+	e.buf = appendCBORHead(e.buf, cborMajorNegInt, uint64(-1-i))
+}
+
+func (e *Event) cborUint64(i uint64) {
+	e.buf = appendCBORHead(e.buf, cborMajorUint, i)
+}
+
+func (e *Event) cborFloat64(f float64) {
+	e.buf = append(e.buf, cborFloat64)
+	bits := math.Float64bits(f)
+	e.buf = append(e.buf, byte(bits>>56), byte(bits>>48), byte(bits>>40), byte(bits>>32), byte(bits>>24), byte(bits>>16), byte(bits>>8), byte(bits))
+}
+
+func (e *Event) cborBool(b bool) {
+	if b {
+		e.buf = append(e.buf, cborTrue)
+	} else {
+		e.buf = append(e.buf, cborFalse)
+	}
+}
+
+func (e *Event) cborArrayHeader(n int) {
+	e.buf = appendCBORHead(e.buf, cborMajorArray, uint64(n))
+}
+
+func (e *Event) cborTime(t time.Time) {
+	e.cborStr(t.UTC().Format("2006-01-02T15:04:05.999Z"))
+}
+
+// cborValue encodes an arbitrary Go value as native CBOR, recursing into
+// slices and maps instead of falling back to a JSON/base64 detour. Types it
+// doesn't recognize directly are round-tripped through encoding/json so the
+// resulting structure still matches what Interface would have marshaled.
+func (e *Event) cborValue(i interface{}) {
+	switch v := i.(type) {
+	case nil:
+		e.buf = append(e.buf, cborNull)
+	case bool:
+		e.cborBool(v)
+	case string:
+		e.cborStr(v)
+	case []byte:
+		e.cborBytes(v)
+	case int:
+		e.cborInt64(int64(v))
+	case int8:
+		e.cborInt64(int64(v))
+	case int16:
+		e.cborInt64(int64(v))
+	case int32:
+		e.cborInt64(int64(v))
+	case int64:
+		e.cborInt64(v)
+	case uint:
+		e.cborUint64(uint64(v))
+	case uint8:
+		e.cborUint64(uint64(v))
+	case uint16:
+		e.cborUint64(uint64(v))
+	case uint32:
+		e.cborUint64(uint64(v))
+	case uint64:
+		e.cborUint64(v)
+	case float32:
+		e.cborFloat64(float64(v))
+	case float64:
+		e.cborFloat64(v)
+	case []interface{}:
+		e.cborArrayHeader(len(v))
+		for _, item := range v {
+			e.cborValue(item)
+		}
+	case map[string]interface{}:
+		e.buf = appendCBORHead(e.buf, cborMajorMap, uint64(len(v)))
+		for k, val := range v {
+			e.cborStr(k)
+			e.cborValue(val)
+		}
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			e.cborInt64(n)
+			return
+		}
+		f, _ := v.Float64()
+		e.cborFloat64(f)
+	default:
+		marshaled, err := json.Marshal(i)
+		if err != nil {
+			e.cborStr("marshaling error: " + err.Error())
+			return
+		}
+		dec := json.NewDecoder(bytes.NewReader(marshaled))
+		dec.UseNumber()
+		var generic interface{}
+		if err := dec.Decode(&generic); err != nil {
+			e.cborStr("marshaling error: " + err.Error())
+			return
+		}
+		e.cborValue(generic)
+	}
+}
+
+// DecodeCBOR decodes a single CBOR-encoded value from data, returning the
+// decoded Go value (map[string]interface{}, []interface{}, string, []byte,
+// int64, uint64, float64, bool or nil) and the number of bytes consumed.
+// It understands exactly the subset of RFC 7049 that Event emits, which is
+// enough to round-trip logs in tests and debugging tools.
+func DecodeCBOR(data []byte) (interface{}, int, error) {
+	if len(data) == 0 {
+		return nil, 0, errors.New("log: empty cbor input")
+	}
+	ib := data[0]
+	major := ib >> 5
+	info := ib & 0x1f
+	switch major {
+	case cborMajorUint:
+		n, sz, err := cborReadUint(data)
+		return n, sz, err
+	case cborMajorNegInt:
+		n, sz, err := cborReadUint(data)
+		if err != nil {
+			return nil, 0, err
+		}
+		return -1 - int64(n), sz, nil
+	case cborMajorBytes:
+		n, sz, err := cborReadUint(data)
+		if err != nil {
+			return nil, 0, err
+		}
+		end := sz + int(n)
+		if end > len(data) {
+			return nil, 0, errors.New("log: truncated cbor byte string")
+		}
+		b := make([]byte, n)
+		copy(b, data[sz:end])
+		return b, end, nil
+	case cborMajorText:
+		n, sz, err := cborReadUint(data)
+		if err != nil {
+			return nil, 0, err
+		}
+		end := sz + int(n)
+		if end > len(data) {
+			return nil, 0, errors.New("log: truncated cbor text string")
+		}
+		return string(data[sz:end]), end, nil
+	case cborMajorArray:
+		return cborReadArray(data, info)
+	case cborMajorMap:
+		return cborReadMap(data, info)
+	case cborMajorSimple:
+		switch ib {
+		case cborFalse:
+			return false, 1, nil
+		case cborTrue:
+			return true, 1, nil
+		case cborNull:
+			return nil, 1, nil
+		case cborFloat64:
+			if len(data) < 9 {
+				return nil, 0, errors.New("log: truncated cbor float64")
+			}
+			var bits uint64
+			for i := 1; i <= 8; i++ {
+				bits = bits<<8 | uint64(data[i])
+			}
+			return math.Float64frombits(bits), 9, nil
+		}
+	}
+	return nil, 0, fmt.Errorf("log: unsupported cbor major type %d", major)
+}
+
+func cborReadArray(data []byte, info byte) (interface{}, int, error) {
+	items := []interface{}{}
+	if info == 31 {
+		pos := 1
+		for {
+			if pos >= len(data) {
+				return nil, 0, errors.New("log: truncated cbor array")
+			}
+			if data[pos] == cborBreak {
+				break
+			}
+			v, sz, err := DecodeCBOR(data[pos:])
+			if err != nil {
+				return nil, 0, err
+			}
+			items = append(items, v)
+			pos += sz
+		}
+		return items, pos + 1, nil
+	}
+	n, sz, err := cborReadUint(data)
+	if err != nil {
+		return nil, 0, err
+	}
+	pos := sz
+	for i := uint64(0); i < n; i++ {
+		v, s, err := DecodeCBOR(data[pos:])
+		if err != nil {
+			return nil, 0, err
+		}
+		items = append(items, v)
+		pos += s
+	}
+	return items, pos, nil
+}
+
+func cborReadMap(data []byte, info byte) (interface{}, int, error) {
+	m := map[string]interface{}{}
+	readPair := func(pos int) (int, error) {
+		k, sz, err := DecodeCBOR(data[pos:])
+		if err != nil {
+			return 0, err
+		}
+		pos += sz
+		v, sz, err := DecodeCBOR(data[pos:])
+		if err != nil {
+			return 0, err
+		}
+		pos += sz
+		if key, ok := k.(string); ok {
+			m[key] = v
+		}
+		return pos, nil
+	}
+	if info == 31 {
+		pos := 1
+		for {
+			if pos >= len(data) {
+				return nil, 0, errors.New("log: truncated cbor map")
+			}
+			if data[pos] == cborBreak {
+				break
+			}
+			next, err := readPair(pos)
+			if err != nil {
+				return nil, 0, err
+			}
+			pos = next
+		}
+		return m, pos + 1, nil
+	}
+	n, sz, err := cborReadUint(data)
+	if err != nil {
+		return nil, 0, err
+	}
+	pos := sz
+	for i := uint64(0); i < n; i++ {
+		next, err := readPair(pos)
+		if err != nil {
+			return nil, 0, err
+		}
+		pos = next
+	}
+	return m, pos, nil
+}
+
+func cborReadUint(data []byte) (uint64, int, error) {
+	info := data[0] & 0x1f
+	switch {
+	case info < 24:
+		return uint64(info), 1, nil
+	case info == 24:
+		if len(data) < 2 {
+			return 0, 0, errors.New("log: truncated cbor uint8")
+		}
+		return uint64(data[1]), 2, nil
+	case info == 25:
+		if len(data) < 3 {
+			return 0, 0, errors.New("log: truncated cbor uint16")
+		}
+		return uint64(data[1])<<8 | uint64(data[2]), 3, nil
+	case info == 26:
+		if len(data) < 5 {
+			return 0, 0, errors.New("log: truncated cbor uint32")
+		}
+		return uint64(data[1])<<24 | uint64(data[2])<<16 | uint64(data[3])<<8 | uint64(data[4]), 5, nil
+	case info == 27:
+		if len(data) < 9 {
+			return 0, 0, errors.New("log: truncated cbor uint64")
+		}
+		var n uint64
+		for i := 1; i <= 8; i++ {
+			n = n<<8 | uint64(data[i])
+		}
+		return n, 9, nil
+	}
+	return 0, 0, fmt.Errorf("log: unsupported cbor additional info %d", info)
+}
+
+// CBORToJSON decodes a single CBOR-encoded Event, as produced by a Logger
+// with Format set to FormatCBOR, and re-renders it as a JSON line, so
+// tooling that only understands this package's JSON output keeps working
+// unchanged.
+func CBORToJSON(data []byte) ([]byte, error) {
+	v, _, err := DecodeCBOR(data)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
@@ -0,0 +1,106 @@
+package log
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCBORValueRoundTrip(t *testing.T) {
+	cases := []interface{}{
+		nil,
+		true,
+		false,
+		"hello",
+		[]byte("bytes"),
+		int64(-42),
+		uint64(42),
+		3.5,
+		[]interface{}{uint64(1), "two", 3.0},
+		map[string]interface{}{"a": uint64(1), "b": "two"},
+	}
+	for _, want := range cases {
+		e := &Event{format: FormatCBOR}
+		e.cborValue(want)
+		got, sz, err := DecodeCBOR(e.buf)
+		if err != nil {
+			t.Fatalf("DecodeCBOR(%#v): %v", want, err)
+		}
+		if sz != len(e.buf) {
+			t.Fatalf("DecodeCBOR(%#v): consumed %d bytes, want %d", want, sz, len(e.buf))
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("round trip of %#v: got %#v", want, got)
+		}
+	}
+}
+
+// TestCBORValueFallbackPreservesIntPrecision proves the encoding/json
+// fallback path used for types cborValue doesn't recognize directly
+// (here, a struct) forwards integers natively via json.Number instead of
+// losing precision through a float64 detour.
+func TestCBORValueFallbackPreservesIntPrecision(t *testing.T) {
+	type payload struct {
+		N int64 `json:"n"`
+	}
+	want := int64(9007199254740993) // 2^53 + 1, not exactly representable as float64
+	e := &Event{format: FormatCBOR}
+	e.cborValue(payload{N: want})
+
+	got, _, err := DecodeCBOR(e.buf)
+	if err != nil {
+		t.Fatalf("DecodeCBOR: %v", err)
+	}
+	m, ok := got.(map[string]interface{})
+	if !ok {
+		t.Fatalf("got %T, want map[string]interface{}", got)
+	}
+	n, ok := m["n"].(uint64)
+	if !ok {
+		t.Fatalf("m[\"n\"] is %T, want uint64", m["n"])
+	}
+	if n != uint64(want) {
+		t.Errorf("m[\"n\"] = %d, want %d", n, want)
+	}
+}
+
+func TestDecodeCBORMalformedIndefiniteArray(t *testing.T) {
+	// An indefinite-length array header (0x9f) with no elements and no
+	// break byte: must return an error, not panic.
+	_, _, err := DecodeCBOR([]byte{0x9f})
+	if err == nil {
+		t.Fatal("DecodeCBOR: expected error for truncated indefinite-length array, got nil")
+	}
+}
+
+func TestDecodeCBORMalformedIndefiniteMap(t *testing.T) {
+	// An indefinite-length map header (0xbf) with no pairs and no break
+	// byte: must return an error, not panic.
+	_, _, err := DecodeCBOR([]byte{0xbf})
+	if err == nil {
+		t.Fatal("DecodeCBOR: expected error for truncated indefinite-length map, got nil")
+	}
+}
+
+func TestDecodeCBORTruncatedNestedIndefiniteArray(t *testing.T) {
+	// Indefinite array containing one valid text string but cut off
+	// before the break byte.
+	data := []byte{0x9f, 0x61, 'a'}
+	_, _, err := DecodeCBOR(data)
+	if err == nil {
+		t.Fatal("DecodeCBOR: expected error for truncated nested indefinite-length array, got nil")
+	}
+}
+
+func TestCBORToJSON(t *testing.T) {
+	e := &Event{format: FormatCBOR}
+	e.cborValue(map[string]interface{}{"msg": "hi", "n": int64(7)})
+
+	got, err := CBORToJSON(e.buf)
+	if err != nil {
+		t.Fatalf("CBORToJSON: %v", err)
+	}
+	want := `{"msg":"hi","n":7}`
+	if string(got) != want {
+		t.Errorf("CBORToJSON = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,367 @@
+package log
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var defaultPartsOrder = []string{"time", "level", "caller", "message"}
+
+// ConsoleWriter parses the JSON lines produced by this package's Logger
+// and re-renders them as colored, human-readable output for local
+// development: dim timestamp, level colored by severity, bold message,
+// then the remaining fields as sorted "key=value" pairs.
+type ConsoleWriter struct {
+	Out        io.Writer
+	NoColor    bool
+	TimeFormat string
+	PartsOrder []string
+
+	FormatLevel      func(interface{}) string
+	FormatMessage    func(interface{}) string
+	FormatFieldName  func(interface{}) string
+	FormatFieldValue func(interface{}) string
+
+	once  sync.Once
+	color bool
+}
+
+func (w *ConsoleWriter) init() {
+	w.color = !w.NoColor
+	if !w.color {
+		return
+	}
+	f, ok := w.out().(*os.File)
+	w.color = ok && isTerminal(f)
+}
+
+func (w *ConsoleWriter) out() io.Writer {
+	if w.Out == nil {
+		return os.Stdout
+	}
+	return w.Out
+}
+
+// Write implements io.Writer. Lines it cannot parse as a JSON object
+// produced by this package (e.g. a multi-line Fatal stack dump) are
+// passed through unchanged.
+func (w *ConsoleWriter) Write(p []byte) (int, error) {
+	w.once.Do(w.init)
+
+	line := bytes.TrimRight(p, "\n")
+	fields, err := scanJSONObject(line)
+	if err != nil {
+		return w.out().Write(p)
+	}
+
+	byKey := make(map[string]string, len(fields))
+	for _, f := range fields {
+		byKey[f.key] = f.raw
+	}
+
+	order := w.PartsOrder
+	if order == nil {
+		order = defaultPartsOrder
+	}
+
+	var buf bytes.Buffer
+	used := make(map[string]bool, len(order))
+	for _, part := range order {
+		raw, ok := byKey[part]
+		if !ok {
+			continue
+		}
+		used[part] = true
+		if buf.Len() > 0 {
+			buf.WriteByte(' ')
+		}
+		buf.WriteString(w.formatPart(part, raw))
+	}
+
+	rest := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if !used[f.key] {
+			rest = append(rest, f.key)
+		}
+	}
+	sort.Strings(rest)
+	for _, k := range rest {
+		if buf.Len() > 0 {
+			buf.WriteByte(' ')
+		}
+		buf.WriteString(w.formatFieldName(k))
+		buf.WriteByte('=')
+		buf.WriteString(w.formatFieldValue(byKey[k]))
+	}
+	buf.WriteByte('\n')
+
+	if _, err := w.out().Write(buf.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (w *ConsoleWriter) formatPart(part string, raw string) string {
+	switch part {
+	case "time":
+		return w.formatTime(raw)
+	case "level":
+		return w.formatLevel(raw)
+	case "message":
+		return w.formatMessage(raw)
+	case "caller":
+		return w.colorize(unquoteJSONLiteral(raw), colorCyan)
+	default:
+		return w.formatFieldValue(raw)
+	}
+}
+
+func (w *ConsoleWriter) formatTime(raw string) string {
+	s := unquoteJSONLiteral(raw)
+	if w.TimeFormat != "" {
+		if t, err := time.Parse("2006-01-02T15:04:05.999Z", s); err == nil {
+			s = t.Format(w.TimeFormat)
+		}
+	}
+	return w.colorize(s, colorFaint)
+}
+
+func (w *ConsoleWriter) formatLevel(raw string) string {
+	s := unquoteJSONLiteral(raw)
+	if w.FormatLevel != nil {
+		return w.FormatLevel(s)
+	}
+	color := colorWhite
+	switch s {
+	case "debug":
+		color = colorMagenta
+	case "info":
+		color = colorGreen
+	case "warn":
+		color = colorYellow
+	case "error", "fatal":
+		color = colorRed
+	}
+	return w.colorize(fmt.Sprintf("%-5s", s), color)
+}
+
+func (w *ConsoleWriter) formatMessage(raw string) string {
+	s := unquoteJSONLiteral(raw)
+	if w.FormatMessage != nil {
+		return w.FormatMessage(s)
+	}
+	return w.colorize(s, colorBold)
+}
+
+func (w *ConsoleWriter) formatFieldName(key string) string {
+	if w.FormatFieldName != nil {
+		return w.FormatFieldName(key)
+	}
+	return w.colorize(key, colorCyan)
+}
+
+func (w *ConsoleWriter) formatFieldValue(raw string) string {
+	s := unquoteJSONLiteral(raw)
+	if w.FormatFieldValue != nil {
+		return w.FormatFieldValue(s)
+	}
+	return s
+}
+
+const (
+	colorBlack = iota + 30
+	colorRed
+	colorGreen
+	colorYellow
+	colorBlue
+	colorMagenta
+	colorCyan
+	colorWhite
+)
+
+const (
+	colorBold  = 1
+	colorFaint = 2
+)
+
+func (w *ConsoleWriter) colorize(s string, code int) string {
+	if !w.color {
+		return s
+	}
+	return fmt.Sprintf("\x1b[%dm%s\x1b[0m", code, s)
+}
+
+// unquoteJSONLiteral strips the surrounding quotes and unescapes a JSON
+// string literal. Non-string literals (numbers, true/false/null, nested
+// objects/arrays) are returned unchanged.
+func unquoteJSONLiteral(raw string) string {
+	if len(raw) < 2 || raw[0] != '"' || raw[len(raw)-1] != '"' {
+		return raw
+	}
+	raw = raw[1 : len(raw)-1]
+	if bytes.IndexByte([]byte(raw), '\\') < 0 {
+		return raw
+	}
+	var buf bytes.Buffer
+	for i := 0; i < len(raw); i++ {
+		if raw[i] != '\\' || i+1 >= len(raw) {
+			buf.WriteByte(raw[i])
+			continue
+		}
+		i++
+		switch raw[i] {
+		case 'n':
+			buf.WriteByte('\n')
+		case 't':
+			buf.WriteByte('\t')
+		case 'r':
+			buf.WriteByte('\r')
+		case '"', '\\', '/':
+			buf.WriteByte(raw[i])
+		case 'u':
+			if i+5 <= len(raw) {
+				if r, err := strconv.ParseUint(raw[i+1:i+5], 16, 32); err == nil {
+					buf.WriteRune(rune(r))
+					i += 4
+					continue
+				}
+			}
+			buf.WriteByte(raw[i])
+		default:
+			buf.WriteByte(raw[i])
+		}
+	}
+	return buf.String()
+}
+
+// isTerminal reports whether f is a character device, i.e. a terminal
+// rather than a redirected file or pipe.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+type jsonField struct {
+	key string
+	raw string
+}
+
+// scanJSONObject walks a single top-level JSON object, as emitted by
+// Logger's JSON output, into its key/value pairs without a full
+// encoding/json unmarshal. Each value is returned as its raw JSON span so
+// callers can decide how to render it (string literals are unescaped on
+// demand via unquoteJSONLiteral).
+func scanJSONObject(line []byte) ([]jsonField, error) {
+	i, n := 0, len(line)
+	for i < n && (line[i] == ' ' || line[i] == '\t') {
+		i++
+	}
+	if i >= n || line[i] != '{' {
+		return nil, errors.New("log: console writer: not a json object")
+	}
+	i++
+
+	var fields []jsonField
+	for {
+		for i < n && (line[i] == ' ' || line[i] == '\t' || line[i] == ',') {
+			i++
+		}
+		if i < n && line[i] == '}' {
+			i++
+			break
+		}
+		if i >= n || line[i] != '"' {
+			return nil, errors.New("log: console writer: expected object key")
+		}
+		keyStart := i
+		i++
+		for i < n && line[i] != '"' {
+			if line[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= n {
+			return nil, errors.New("log: console writer: unterminated key")
+		}
+		key := unquoteJSONLiteral(string(line[keyStart : i+1]))
+		i++
+
+		for i < n && (line[i] == ' ' || line[i] == '\t') {
+			i++
+		}
+		if i >= n || line[i] != ':' {
+			return nil, errors.New("log: console writer: expected ':'")
+		}
+		i++
+		for i < n && (line[i] == ' ' || line[i] == '\t') {
+			i++
+		}
+		if i >= n {
+			return nil, errors.New("log: console writer: missing value")
+		}
+
+		valStart := i
+		switch line[i] {
+		case '"':
+			i++
+			for i < n && line[i] != '"' {
+				if line[i] == '\\' {
+					i++
+				}
+				i++
+			}
+			if i >= n {
+				return nil, errors.New("log: console writer: unterminated string value")
+			}
+			i++
+		case '{', '[':
+			open, close := line[i], byte('}')
+			if open == '[' {
+				close = ']'
+			}
+			depth := 0
+		scan:
+			for i < n {
+				switch line[i] {
+				case '"':
+					i++
+					for i < n && line[i] != '"' {
+						if line[i] == '\\' {
+							i++
+						}
+						i++
+					}
+				case open:
+					depth++
+				case close:
+					depth--
+					if depth == 0 {
+						i++
+						break scan
+					}
+				}
+				i++
+			}
+			if i > n {
+				return nil, errors.New("log: console writer: unterminated nested value")
+			}
+		default:
+			for i < n && line[i] != ',' && line[i] != '}' {
+				i++
+			}
+		}
+		fields = append(fields, jsonField{key: key, raw: string(line[valStart:i])})
+	}
+	return fields, nil
+}
@@ -0,0 +1,34 @@
+package log
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestConsoleWriterPassesThroughUnterminatedLine proves Write falls back
+// to passing a line through unchanged, as its doc comment promises,
+// instead of panicking when the last field's string value is missing its
+// closing quote.
+func TestConsoleWriterPassesThroughUnterminatedLine(t *testing.T) {
+	var out bytes.Buffer
+	w := &ConsoleWriter{Out: &out, NoColor: true}
+
+	line := []byte(`{"time":"2026-07-25T00:00:00Z","level":"info","message":"unterminated`)
+	n, err := w.Write(line)
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if n != len(line) {
+		t.Errorf("Write returned n=%d, want %d", n, len(line))
+	}
+	if out.String() != string(line) {
+		t.Errorf("Write output = %q, want passthrough of %q", out.String(), line)
+	}
+}
+
+func TestScanJSONObjectUnterminatedStringValue(t *testing.T) {
+	_, err := scanJSONObject([]byte(`{"message":"unterminated`))
+	if err == nil {
+		t.Fatal("scanJSONObject: expected error for unterminated string value, got nil")
+	}
+}
@@ -0,0 +1,153 @@
+package log
+
+import (
+	"context"
+	"time"
+)
+
+// Context builds up a set of fields to attach to every Event produced by
+// a Logger, without re-supplying them at every call site. Obtain one from
+// Logger.With, add fields with the same names as the Event methods, then
+// call Logger to get back a Logger that always includes them.
+type Context struct {
+	l   Logger
+	buf []byte
+}
+
+// With starts a Context seeded with any fields l already carries, so
+// sub-loggers can be chained.
+func (l Logger) With() Context {
+	return Context{l: l, buf: append([]byte(nil), l.context...)}
+}
+
+// Logger returns a Logger that splices c's accumulated fields into every
+// Event it produces, right after the level field.
+func (c Context) Logger() Logger {
+	l := c.l
+	l.context = c.buf
+	return l
+}
+
+// scratch returns an Event wrapping a copy of c.buf so Context can reuse
+// Event's field-serialization logic (JSON or CBOR, whichever c.l.Format
+// selects) instead of duplicating it. It copies rather than aliasing
+// c.buf because append's spare capacity would otherwise let two Contexts
+// forked from the same parent (c1, c2 := base.Str(...), base.Str(...))
+// clobber each other's fields in place.
+func (c Context) scratch() *Event {
+	return &Event{buf: append([]byte(nil), c.buf...), escapeHTML: c.l.EscapeHTML, format: c.l.Format}
+}
+
+// Str adds a string field.
+func (c Context) Str(key, val string) Context {
+	e := c.scratch()
+	e.Str(key, val)
+	c.buf = e.buf
+	return c
+}
+
+// Strs adds a string slice field.
+func (c Context) Strs(key string, vals []string) Context {
+	e := c.scratch()
+	e.Strs(key, vals)
+	c.buf = e.buf
+	return c
+}
+
+// Bytes adds a []byte field.
+func (c Context) Bytes(key string, val []byte) Context {
+	e := c.scratch()
+	e.Bytes(key, val)
+	c.buf = e.buf
+	return c
+}
+
+// Bool adds a bool field.
+func (c Context) Bool(key string, b bool) Context {
+	e := c.scratch()
+	e.Bool(key, b)
+	c.buf = e.buf
+	return c
+}
+
+// Int adds an int field.
+func (c Context) Int(key string, i int) Context {
+	e := c.scratch()
+	e.Int(key, i)
+	c.buf = e.buf
+	return c
+}
+
+// Int64 adds an int64 field.
+func (c Context) Int64(key string, i int64) Context {
+	e := c.scratch()
+	e.Int64(key, i)
+	c.buf = e.buf
+	return c
+}
+
+// Uint64 adds a uint64 field.
+func (c Context) Uint64(key string, i uint64) Context {
+	e := c.scratch()
+	e.Uint64(key, i)
+	c.buf = e.buf
+	return c
+}
+
+// Float64 adds a float64 field.
+func (c Context) Float64(key string, f float64) Context {
+	e := c.scratch()
+	e.Float64(key, f)
+	c.buf = e.buf
+	return c
+}
+
+// Dur adds a time.Duration field.
+func (c Context) Dur(key string, d time.Duration) Context {
+	e := c.scratch()
+	e.Dur(key, d)
+	c.buf = e.buf
+	return c
+}
+
+// Time adds a time.Time field.
+func (c Context) Time(key string, t time.Time) Context {
+	e := c.scratch()
+	e.Time(key, t)
+	c.buf = e.buf
+	return c
+}
+
+// Err adds an "error" field.
+func (c Context) Err(err error) Context {
+	e := c.scratch()
+	e.Err(err)
+	c.buf = e.buf
+	return c
+}
+
+// Interface adds a field holding the JSON (or CBOR) marshaling of i.
+func (c Context) Interface(key string, i interface{}) Context {
+	e := c.scratch()
+	e.Interface(key, i)
+	c.buf = e.buf
+	return c
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying l, for request-scoped fields
+// (request_id, user_id, trace_id, ...) to flow through context.Context in
+// HTTP/gRPC middleware instead of being re-supplied at every call site.
+func NewContext(ctx context.Context, l Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// FromContext returns the Logger stored in ctx by NewContext, or
+// DefaultLogger if none was stored.
+func FromContext(ctx context.Context) Logger {
+	if l, ok := ctx.Value(contextKey{}).(Logger); ok {
+		return l
+	}
+	return DefaultLogger
+}
@@ -0,0 +1,33 @@
+package log
+
+import "testing"
+
+// TestContextForkDoesNotAliasParentBuf proves two Contexts derived from
+// the same parent Context don't share a backing array: appending a field
+// to one must not corrupt a field already committed by the other, even
+// when the parent's buffer has spare capacity.
+func TestContextForkDoesNotAliasParentBuf(t *testing.T) {
+	base := DefaultLogger.With().Str("request_id", "abc123").Str("user_id", "u-42")
+
+	child1 := base.Str("component", "alpha")
+	child2 := base.Str("component", "beta")
+
+	l1 := child1.Logger()
+	l2 := child2.Logger()
+
+	if got := string(l1.context); !contains(got, `"component":"alpha"`) {
+		t.Errorf("child1 context = %s, want to contain component=alpha", got)
+	}
+	if got := string(l2.context); !contains(got, `"component":"beta"`) {
+		t.Errorf("child2 context = %s, want to contain component=beta", got)
+	}
+}
+
+func contains(s, substr string) bool {
+	for i := 0; i+len(substr) <= len(s); i++ {
+		if s[i:i+len(substr)] == substr {
+			return true
+		}
+	}
+	return false
+}
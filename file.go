@@ -0,0 +1,213 @@
+package log
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RollingFileWriter writes to Filename, rotating the active file before a
+// write that would take it past MaxSize or when the clock crosses
+// RotateAt, gzip-compressing
+// (if Compress) and pruning old segments in the background. Write is
+// safe for concurrent use by multiple Event.Send calls.
+type RollingFileWriter struct {
+	Filename   string
+	MaxSize    int64
+	MaxBackups int
+	MaxAge     time.Duration
+	LocalTime  bool
+	Compress   bool
+	// RotateAt, if set, forces a cron-like rotation the first time Write
+	// is called at or after the next occurrence of this schedule, then
+	// again every period from there. It accepts a daily time of day in
+	// "15:04" or "15:04:05" form, or an hourly form ":04" or ":04:05"
+	// (a leading colon, minute[:second]) to rotate at that minute of
+	// every hour.
+	RotateAt string
+
+	mu           sync.Mutex
+	file         *os.File
+	size         int64
+	nextRotateAt time.Time
+	hupOnce      sync.Once
+	cleanupMu    sync.Mutex
+}
+
+// Write implements io.Writer.
+func (w *RollingFileWriter) Write(p []byte) (n int, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.hupOnce.Do(w.watchSIGHUP)
+
+	if w.file == nil {
+		if err = w.openLocked(); err != nil {
+			return 0, err
+		}
+	}
+	if w.shouldRotateLocked(len(p)) {
+		if err = w.rotateLocked(); err != nil {
+			return 0, err
+		}
+	}
+	n, err = w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+func (w *RollingFileWriter) shouldRotateLocked(next int) bool {
+	if w.MaxSize > 0 && w.size+int64(next) > w.MaxSize {
+		return true
+	}
+	if !w.nextRotateAt.IsZero() && !timeNow().Before(w.nextRotateAt) {
+		return true
+	}
+	return false
+}
+
+func (w *RollingFileWriter) openLocked() error {
+	if dir := filepath.Dir(w.Filename); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	f, err := os.OpenFile(w.Filename, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	w.file = f
+	w.size = info.Size()
+	w.nextRotateAt = w.computeNextRotateAt()
+	return nil
+}
+
+func (w *RollingFileWriter) computeNextRotateAt() time.Time {
+	if w.RotateAt == "" {
+		return time.Time{}
+	}
+	now := timeNow()
+	if !w.LocalTime {
+		now = now.UTC()
+	}
+	if strings.HasPrefix(w.RotateAt, ":") {
+		for _, layout := range []string{":04:05", ":04"} {
+			if t, err := time.ParseInLocation(layout, w.RotateAt, now.Location()); err == nil {
+				next := time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), t.Minute(), t.Second(), 0, now.Location())
+				if !next.After(now) {
+					next = next.Add(time.Hour)
+				}
+				return next
+			}
+		}
+		return time.Time{}
+	}
+	for _, layout := range []string{"15:04:05", "15:04"} {
+		if t, err := time.ParseInLocation(layout, w.RotateAt, now.Location()); err == nil {
+			next := time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), t.Second(), 0, now.Location())
+			if !next.After(now) {
+				next = next.Add(24 * time.Hour)
+			}
+			return next
+		}
+	}
+	return time.Time{}
+}
+
+// rotateLocked closes the active file, renames it with a timestamp
+// suffix, reopens Filename, and kicks off background compression and
+// pruning of old segments.
+func (w *RollingFileWriter) rotateLocked() error {
+	if w.file != nil {
+		w.file.Close()
+		w.file = nil
+	}
+	now := timeNow()
+	if !w.LocalTime {
+		now = now.UTC()
+	}
+	backup := fmt.Sprintf("%s-%s.log", strings.TrimSuffix(w.Filename, filepath.Ext(w.Filename)), now.Format("2006-01-02T15-04-05.000"))
+	if err := os.Rename(w.Filename, backup); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	go w.cleanup(backup)
+	return w.openLocked()
+}
+
+// cleanup gzip-compresses the just-rotated backup (if Compress) and
+// prunes segments beyond MaxBackups/MaxAge. It runs off the write path
+// so slow disks or many backups never block Write.
+func (w *RollingFileWriter) cleanup(backup string) {
+	w.cleanupMu.Lock()
+	defer w.cleanupMu.Unlock()
+
+	if w.Compress {
+		gzipFile(backup)
+	}
+
+	pattern := strings.TrimSuffix(w.Filename, filepath.Ext(w.Filename)) + "-*"
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return
+	}
+	sort.Strings(matches)
+
+	cutoff := time.Time{}
+	if w.MaxAge > 0 {
+		cutoff = timeNow().Add(-w.MaxAge)
+	}
+	keep := len(matches)
+	if w.MaxBackups > 0 && keep > w.MaxBackups {
+		for _, old := range matches[:keep-w.MaxBackups] {
+			os.Remove(old)
+		}
+		matches = matches[keep-w.MaxBackups:]
+	}
+	if !cutoff.IsZero() {
+		for _, old := range matches {
+			if info, err := os.Stat(old); err == nil && info.ModTime().Before(cutoff) {
+				os.Remove(old)
+			}
+		}
+	}
+}
+
+func gzipFile(name string) (string, error) {
+	src, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+
+	dstName := name + ".gz"
+	dst, err := os.OpenFile(dstName, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return "", err
+	}
+	gz := gzip.NewWriter(dst)
+	if _, err = io.Copy(gz, src); err != nil {
+		gz.Close()
+		dst.Close()
+		return "", err
+	}
+	if err = gz.Close(); err != nil {
+		dst.Close()
+		return "", err
+	}
+	if err = dst.Close(); err != nil {
+		return "", err
+	}
+	os.Remove(name)
+	return dstName, nil
+}
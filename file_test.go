@@ -0,0 +1,62 @@
+package log
+
+import (
+	"testing"
+	"time"
+)
+
+func withFixedNow(t *testing.T, now time.Time, fn func()) {
+	t.Helper()
+	orig := timeNow
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = orig }()
+	fn()
+}
+
+func TestComputeNextRotateAtDaily(t *testing.T) {
+	now := time.Date(2026, 7, 25, 10, 30, 0, 0, time.UTC)
+	withFixedNow(t, now, func() {
+		w := &RollingFileWriter{RotateAt: "15:00"}
+		got := w.computeNextRotateAt()
+		want := time.Date(2026, 7, 25, 15, 0, 0, 0, time.UTC)
+		if !got.Equal(want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+}
+
+func TestComputeNextRotateAtDailyRollsToNextDay(t *testing.T) {
+	now := time.Date(2026, 7, 25, 16, 30, 0, 0, time.UTC)
+	withFixedNow(t, now, func() {
+		w := &RollingFileWriter{RotateAt: "15:00"}
+		got := w.computeNextRotateAt()
+		want := time.Date(2026, 7, 26, 15, 0, 0, 0, time.UTC)
+		if !got.Equal(want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+}
+
+func TestComputeNextRotateAtHourly(t *testing.T) {
+	now := time.Date(2026, 7, 25, 10, 15, 0, 0, time.UTC)
+	withFixedNow(t, now, func() {
+		w := &RollingFileWriter{RotateAt: ":30"}
+		got := w.computeNextRotateAt()
+		want := time.Date(2026, 7, 25, 10, 30, 0, 0, time.UTC)
+		if !got.Equal(want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+}
+
+func TestComputeNextRotateAtHourlyRollsToNextHour(t *testing.T) {
+	now := time.Date(2026, 7, 25, 10, 45, 0, 0, time.UTC)
+	withFixedNow(t, now, func() {
+		w := &RollingFileWriter{RotateAt: ":30"}
+		got := w.computeNextRotateAt()
+		want := time.Date(2026, 7, 25, 11, 30, 0, 0, time.UTC)
+		if !got.Equal(want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+}
@@ -0,0 +1,28 @@
+//go:build !windows
+
+package log
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// watchSIGHUP reopens the file on SIGHUP so external log rotation tools
+// (logrotate and friends) can move Filename aside and have this writer
+// pick up a fresh one, matching the usual Unix logrotate "copytruncate"
+// or "create" dance.
+func (w *RollingFileWriter) watchSIGHUP() {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGHUP)
+	go func() {
+		for range ch {
+			w.mu.Lock()
+			if w.file != nil {
+				w.file.Close()
+				w.file = nil
+			}
+			w.mu.Unlock()
+		}
+	}()
+}
@@ -0,0 +1,8 @@
+//go:build windows
+
+package log
+
+// watchSIGHUP is a no-op on Windows, which has no SIGHUP: external log
+// rotation there is expected to use Filename directly rather than
+// signaling this process.
+func (w *RollingFileWriter) watchSIGHUP() {}
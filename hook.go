@@ -0,0 +1,45 @@
+package log
+
+// Hook is a side-effect run for every Event, after Msg has appended the
+// message field (Send appends none and passes an empty msg) but before
+// the Event is written out. Run may add
+// further fields to e (e.g. a TraceHook pulling span_id from a
+// goroutine-local context and calling e.Str("trace_id", ...)), making it
+// the integration point for metrics, tracing and alerting.
+type Hook interface {
+	Run(e *Event, level Level, msg string)
+}
+
+// HookFunc adapts a plain function to the Hook interface.
+type HookFunc func(e *Event, level Level, msg string)
+
+// Run implements Hook.
+func (f HookFunc) Run(e *Event, level Level, msg string) {
+	f(e, level, msg)
+}
+
+// LevelHook dispatches to a per-level Hook. A nil entry is a no-op for
+// that level.
+type LevelHook struct {
+	Debug, Info, Warn, Error, Fatal Hook
+}
+
+// Run implements Hook.
+func (h LevelHook) Run(e *Event, level Level, msg string) {
+	var hook Hook
+	switch level {
+	case DebugLevel:
+		hook = h.Debug
+	case InfoLevel:
+		hook = h.Info
+	case WarnLevel:
+		hook = h.Warn
+	case ErrorLevel:
+		hook = h.Error
+	case FatalLevel:
+		hook = h.Fatal
+	}
+	if hook != nil {
+		hook.Run(e, level, msg)
+	}
+}
@@ -0,0 +1,59 @@
+package log
+
+import "testing"
+
+func TestHookFuncRunsUnderlyingFunc(t *testing.T) {
+	var gotLevel Level
+	var gotMsg string
+	h := HookFunc(func(e *Event, level Level, msg string) {
+		gotLevel = level
+		gotMsg = msg
+	})
+
+	h.Run(&Event{}, WarnLevel, "boom")
+
+	if gotLevel != WarnLevel {
+		t.Errorf("level = %v, want %v", gotLevel, WarnLevel)
+	}
+	if gotMsg != "boom" {
+		t.Errorf("msg = %q, want %q", gotMsg, "boom")
+	}
+}
+
+func TestLevelHookDispatchesPerLevel(t *testing.T) {
+	var ran string
+	mk := func(name string) Hook {
+		return HookFunc(func(e *Event, level Level, msg string) { ran = name })
+	}
+	h := LevelHook{
+		Debug: mk("debug"),
+		Info:  mk("info"),
+		Warn:  mk("warn"),
+		Error: mk("error"),
+		Fatal: mk("fatal"),
+	}
+
+	cases := []struct {
+		level Level
+		want  string
+	}{
+		{DebugLevel, "debug"},
+		{InfoLevel, "info"},
+		{WarnLevel, "warn"},
+		{ErrorLevel, "error"},
+		{FatalLevel, "fatal"},
+	}
+	for _, c := range cases {
+		ran = ""
+		h.Run(&Event{}, c.level, "msg")
+		if ran != c.want {
+			t.Errorf("level %v: ran %q, want %q", c.level, ran, c.want)
+		}
+	}
+}
+
+func TestLevelHookNilEntryIsNoop(t *testing.T) {
+	h := LevelHook{}
+	// Must not panic when no hook is configured for the level.
+	h.Run(&Event{}, InfoLevel, "msg")
+}
@@ -15,12 +15,16 @@ import (
 )
 
 var DefaultLogger = Logger{
-	Level:      DebugLevel,
-	Caller:     false,
-	EscapeHTML: false,
-	TimeField:  "",
-	TimeFormat: "",
-	Writer:     &Writer{},
+	Level:          DebugLevel,
+	Caller:         false,
+	EscapeHTML:     false,
+	TimeField:      "",
+	TimeFormat:     "",
+	Format:         FormatJSON,
+	Sampler:        nil,
+	Hooks:          nil,
+	StackMarshaler: nil,
+	Writer:         &Writer{},
 }
 
 type Logger struct {
@@ -29,15 +33,30 @@ type Logger struct {
 	EscapeHTML bool
 	TimeField  string
 	TimeFormat string
-	Writer     io.Writer
+	Format     Format
+	Sampler    Sampler
+	Hooks      []Hook
+	// StackMarshaler formats the program counters captured by Event.Stack
+	// and Event.StackErr into the bytes appended as the "stack" field. A
+	// nil StackMarshaler uses defaultStackMarshaler. It is not used under
+	// FormatCBOR, where the stack is always encoded as a CBOR array of
+	// {func, file, line} maps.
+	StackMarshaler func([]uintptr) []byte
+	Writer         io.Writer
+
+	context []byte
 }
 
 type Event struct {
-	buf        []byte
-	fatal      bool
-	escapeHTML bool
-	timeFormat string
-	write      func(p []byte) (n int, err error)
+	buf            []byte
+	fatal          bool
+	escapeHTML     bool
+	timeFormat     string
+	format         Format
+	level          Level
+	hooks          []Hook
+	stackMarshaler func([]uintptr) []byte
+	write          func(p []byte) (n int, err error)
 }
 
 func Debug() *Event {
@@ -90,14 +109,58 @@ func (l Logger) WithLevel(level Level) (e *Event) {
 	if level < l.Level {
 		return
 	}
+	if l.Sampler != nil && !l.Sampler.Sample(level) {
+		return
+	}
 	e = epool.Get().(*Event)
 	e.buf = e.buf[:0]
 	e.fatal = level == FatalLevel
 	e.escapeHTML = l.EscapeHTML
 	e.timeFormat = l.TimeFormat
+	e.format = l.Format
+	e.level = level
+	e.hooks = l.Hooks
+	e.stackMarshaler = l.StackMarshaler
 	e.write = l.Writer.Write
 	// time
 	now := timeNow()
+	if e.format == FormatCBOR {
+		e.buf = append(e.buf, cborMapOpen)
+		if l.TimeField == "" {
+			e.cborKey("time")
+		} else {
+			e.cborKey(l.TimeField)
+		}
+		if e.timeFormat == "" {
+			e.cborTime(now)
+		} else {
+			e.cborStr(now.Format(e.timeFormat))
+		}
+		switch level {
+		case DebugLevel:
+			e.cborKey("level")
+			e.cborStr("debug")
+		case InfoLevel:
+			e.cborKey("level")
+			e.cborStr("info")
+		case WarnLevel:
+			e.cborKey("level")
+			e.cborStr("warn")
+		case ErrorLevel:
+			e.cborKey("level")
+			e.cborStr("error")
+		case FatalLevel:
+			e.cborKey("level")
+			e.cborStr("fatal")
+		}
+		if len(l.context) > 0 {
+			e.buf = append(e.buf, l.context...)
+		}
+		if l.Caller {
+			e.caller(1)
+		}
+		return
+	}
 	if l.TimeField == "" {
 		e.buf = append(e.buf, "{\"time\":"...)
 	} else {
@@ -123,6 +186,11 @@ func (l Logger) WithLevel(level Level) (e *Event) {
 	case FatalLevel:
 		e.buf = append(e.buf, ",\"level\":\"fatal\""...)
 	}
+	// context fields inherited from Logger.With(), copied so Event pool
+	// reuse never aliases the Logger's fragment.
+	if len(l.context) > 0 {
+		e.buf = append(e.buf, l.context...)
+	}
 	// caller
 	if l.Caller {
 		e.caller(1)
@@ -134,6 +202,15 @@ func (e *Event) Time(key string, t time.Time) *Event {
 	if e == nil {
 		return nil
 	}
+	if e.format == FormatCBOR {
+		e.cborKey(key)
+		if e.timeFormat != "" {
+			e.cborStr(t.Format(e.timeFormat))
+		} else {
+			e.cborTime(t)
+		}
+		return e
+	}
 	e.key(',', key)
 	switch {
 	case e.timeFormat != "":
@@ -150,6 +227,11 @@ func (e *Event) Timestamp() *Event {
 	if e == nil {
 		return nil
 	}
+	if e.format == FormatCBOR {
+		e.cborKey("timestamp")
+		e.cborInt64(timeNow().Unix())
+		return e
+	}
 	e.key(',', "timestamp")
 	e.buf = strconv.AppendInt(e.buf, timeNow().Unix(), 10)
 	return e
@@ -159,6 +241,11 @@ func (e *Event) Bool(key string, b bool) *Event {
 	if e == nil {
 		return nil
 	}
+	if e.format == FormatCBOR {
+		e.cborKey(key)
+		e.cborBool(b)
+		return e
+	}
 	e.key(',', key)
 	e.buf = strconv.AppendBool(e.buf, b)
 	return e
@@ -168,6 +255,14 @@ func (e *Event) Bools(key string, b []bool) *Event {
 	if e == nil {
 		return nil
 	}
+	if e.format == FormatCBOR {
+		e.cborKey(key)
+		e.cborArrayHeader(len(b))
+		for _, a := range b {
+			e.cborBool(a)
+		}
+		return e
+	}
 	e.key(',', key)
 	e.buf = append(e.buf, '[')
 	for i, a := range b {
@@ -184,6 +279,11 @@ func (e *Event) Dur(key string, d time.Duration) *Event {
 	if e == nil {
 		return nil
 	}
+	if e.format == FormatCBOR {
+		e.cborKey(key)
+		e.cborStr(d.String())
+		return e
+	}
 	e.key(',', key)
 	e.buf = append(e.buf, '"')
 	e.buf = append(e.buf, d.String()...)
@@ -195,6 +295,14 @@ func (e *Event) Durs(key string, d []time.Duration) *Event {
 	if e == nil {
 		return nil
 	}
+	if e.format == FormatCBOR {
+		e.cborKey(key)
+		e.cborArrayHeader(len(d))
+		for _, a := range d {
+			e.cborStr(a.String())
+		}
+		return e
+	}
 	e.key(',', key)
 	e.buf = append(e.buf, '[')
 	for i, a := range d {
@@ -213,6 +321,15 @@ func (e *Event) Err(err error) *Event {
 	if e == nil {
 		return nil
 	}
+	if e.format == FormatCBOR {
+		e.cborKey("error")
+		if err == nil {
+			e.buf = append(e.buf, cborNull)
+		} else {
+			e.cborStr(err.Error())
+		}
+		return e
+	}
 	if err == nil {
 		e.buf = append(e.buf, ",\"error\":null"...)
 	} else {
@@ -227,6 +344,19 @@ func (e *Event) Errs(key string, errs []error) *Event {
 		return nil
 	}
 
+	if e.format == FormatCBOR {
+		e.cborKey(key)
+		e.cborArrayHeader(len(errs))
+		for _, err := range errs {
+			if err == nil {
+				e.buf = append(e.buf, cborNull)
+			} else {
+				e.cborStr(err.Error())
+			}
+		}
+		return e
+	}
+
 	e.key(',', key)
 	e.buf = append(e.buf, '[')
 	for i, err := range errs {
@@ -247,6 +377,11 @@ func (e *Event) Float64(key string, f float64) *Event {
 	if e == nil {
 		return nil
 	}
+	if e.format == FormatCBOR {
+		e.cborKey(key)
+		e.cborFloat64(f)
+		return e
+	}
 	e.key(',', key)
 	e.buf = strconv.AppendFloat(e.buf, f, 'f', -1, 64)
 	return e
@@ -256,6 +391,14 @@ func (e *Event) Floats64(key string, f []float64) *Event {
 	if e == nil {
 		return nil
 	}
+	if e.format == FormatCBOR {
+		e.cborKey(key)
+		e.cborArrayHeader(len(f))
+		for _, a := range f {
+			e.cborFloat64(a)
+		}
+		return e
+	}
 	e.key(',', key)
 	e.buf = append(e.buf, '[')
 	for i, a := range f {
@@ -272,6 +415,14 @@ func (e *Event) Floats32(key string, f []float32) *Event {
 	if e == nil {
 		return nil
 	}
+	if e.format == FormatCBOR {
+		e.cborKey(key)
+		e.cborArrayHeader(len(f))
+		for _, a := range f {
+			e.cborFloat64(float64(a))
+		}
+		return e
+	}
 	e.key(',', key)
 	e.buf = append(e.buf, '[')
 	for i, a := range f {
@@ -288,6 +439,11 @@ func (e *Event) Int64(key string, i int64) *Event {
 	if e == nil {
 		return nil
 	}
+	if e.format == FormatCBOR {
+		e.cborKey(key)
+		e.cborInt64(i)
+		return e
+	}
 	e.key(',', key)
 	e.buf = strconv.AppendInt(e.buf, i, 10)
 	return e
@@ -297,6 +453,11 @@ func (e *Event) Uint64(key string, i uint64) *Event {
 	if e == nil {
 		return nil
 	}
+	if e.format == FormatCBOR {
+		e.cborKey(key)
+		e.cborUint64(i)
+		return e
+	}
 	e.key(',', key)
 	e.buf = strconv.AppendUint(e.buf, i, 10)
 	return e
@@ -343,10 +504,26 @@ func (e *Event) RawJSON(key string, b []byte) *Event {
 	return e
 }
 
+// RawCBOR appends a pre-encoded CBOR value verbatim, the CBOR counterpart
+// to RawJSON. It is only meaningful on a Logger using FormatCBOR.
+func (e *Event) RawCBOR(key string, b []byte) *Event {
+	if e == nil {
+		return nil
+	}
+	e.cborKey(key)
+	e.buf = append(e.buf, b...)
+	return e
+}
+
 func (e *Event) Str(key string, val string) *Event {
 	if e == nil {
 		return nil
 	}
+	if e.format == FormatCBOR {
+		e.cborKey(key)
+		e.cborStr(val)
+		return e
+	}
 	e.key(',', key)
 	e.string(val, e.escapeHTML)
 	return e
@@ -356,6 +533,14 @@ func (e *Event) Strs(key string, vals []string) *Event {
 	if e == nil {
 		return nil
 	}
+	if e.format == FormatCBOR {
+		e.cborKey(key)
+		e.cborArrayHeader(len(vals))
+		for _, val := range vals {
+			e.cborStr(val)
+		}
+		return e
+	}
 	e.key(',', key)
 	e.buf = append(e.buf, '[')
 	for i, val := range vals {
@@ -372,6 +557,11 @@ func (e *Event) Bytes(key string, val []byte) *Event {
 	if e == nil {
 		return nil
 	}
+	if e.format == FormatCBOR {
+		e.cborKey(key)
+		e.cborBytes(val)
+		return e
+	}
 	e.key(',', key)
 	e.string(*(*string)(unsafe.Pointer(&val)), e.escapeHTML)
 	return e
@@ -381,6 +571,11 @@ func (e *Event) Interface(key string, i interface{}) *Event {
 	if e == nil {
 		return nil
 	}
+	if e.format == FormatCBOR {
+		e.cborKey(key)
+		e.cborValue(i)
+		return e
+	}
 	e.key(',', key)
 	marshaled, err := json.Marshal(i)
 	if err != nil {
@@ -403,7 +598,14 @@ func (e *Event) Send() {
 	if e == nil {
 		return
 	}
-	e.buf = append(e.buf, '}', '\n')
+	for _, h := range e.hooks {
+		h.Run(e, e.level, "")
+	}
+	if e.format == FormatCBOR {
+		e.buf = append(e.buf, cborBreak)
+	} else {
+		e.buf = append(e.buf, '}', '\n')
+	}
 	e.write(e.buf)
 	if e.fatal {
 		e.write(stacks(false))
@@ -417,9 +619,21 @@ func (e *Event) Msg(msg string) {
 	if e == nil {
 		return
 	}
-	e.buf = append(e.buf, ",\"message\":"...)
-	e.string(msg, e.escapeHTML)
-	e.buf = append(e.buf, '}', '\n')
+	if e.format == FormatCBOR {
+		e.cborKey("message")
+		e.cborStr(msg)
+	} else {
+		e.buf = append(e.buf, ",\"message\":"...)
+		e.string(msg, e.escapeHTML)
+	}
+	for _, h := range e.hooks {
+		h.Run(e, e.level, msg)
+	}
+	if e.format == FormatCBOR {
+		e.buf = append(e.buf, cborBreak)
+	} else {
+		e.buf = append(e.buf, '}', '\n')
+	}
 	e.write(e.buf)
 	if e.fatal {
 		e.write(stacks(false))
@@ -503,6 +717,15 @@ func (e *Event) caller(skip int) {
 	if line < 0 {
 		line = 0
 	}
+	if e.format == FormatCBOR {
+		e.cborKey("caller")
+		buf := make([]byte, 0, len(file)+8)
+		buf = append(buf, file...)
+		buf = append(buf, ':')
+		buf = strconv.AppendInt(buf, int64(line), 10)
+		e.cborStr(*(*string)(unsafe.Pointer(&buf)))
+		return
+	}
 	e.buf = append(e.buf, ",\"caller\":\""...)
 	e.buf = append(e.buf, file...)
 	e.buf = append(e.buf, ':')
@@ -0,0 +1,96 @@
+package log
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Sampler determines whether an Event at the given Level should be
+// logged. Logger.WithLevel consults it before acquiring an Event from
+// epool, so a rejecting Sampler costs nothing beyond the Sample call
+// itself.
+type Sampler interface {
+	Sample(level Level) bool
+}
+
+// BasicSampler accepts every Nth call and drops the rest. A zero N drops
+// every call. It is safe for concurrent use.
+type BasicSampler struct {
+	N uint32
+
+	counter uint32
+}
+
+// Sample implements Sampler.
+func (s *BasicSampler) Sample(level Level) bool {
+	if s.N == 0 {
+		return false
+	}
+	c := atomic.AddUint32(&s.counter, 1) - 1
+	return c%s.N == 0
+}
+
+// BurstSampler lets the first Burst calls in each Period through, then
+// defers to NextSampler (dropping everything if NextSampler is nil)
+// until the period rolls over. It is safe for concurrent use.
+type BurstSampler struct {
+	Burst       uint32
+	Period      time.Duration
+	NextSampler Sampler
+
+	c       uint32
+	resetAt int64
+}
+
+// Sample implements Sampler.
+func (s *BurstSampler) Sample(level Level) bool {
+	if s.Burst > 0 && s.Period > 0 && s.inc() <= s.Burst {
+		return true
+	}
+	if s.NextSampler == nil {
+		return false
+	}
+	return s.NextSampler.Sample(level)
+}
+
+func (s *BurstSampler) inc() uint32 {
+	now := timeNow().UnixNano()
+	resetAt := atomic.LoadInt64(&s.resetAt)
+	if now > resetAt {
+		newResetAt := now + s.Period.Nanoseconds()
+		if atomic.CompareAndSwapInt64(&s.resetAt, resetAt, newResetAt) {
+			atomic.StoreUint32(&s.c, 1)
+			return 1
+		}
+	}
+	return atomic.AddUint32(&s.c, 1)
+}
+
+// LevelSampler dispatches to a per-level Sampler. A nil entry, or
+// FatalLevel (which has none), always accepts.
+type LevelSampler struct {
+	Debug, Info, Warn, Error Sampler
+}
+
+// Sample implements Sampler.
+func (s LevelSampler) Sample(level Level) bool {
+	switch level {
+	case DebugLevel:
+		if s.Debug != nil {
+			return s.Debug.Sample(level)
+		}
+	case InfoLevel:
+		if s.Info != nil {
+			return s.Info.Sample(level)
+		}
+	case WarnLevel:
+		if s.Warn != nil {
+			return s.Warn.Sample(level)
+		}
+	case ErrorLevel:
+		if s.Error != nil {
+			return s.Error.Sample(level)
+		}
+	}
+	return true
+}
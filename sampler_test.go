@@ -0,0 +1,77 @@
+package log
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestBasicSamplerAcceptsEveryNth(t *testing.T) {
+	s := &BasicSampler{N: 3}
+	var got []bool
+	for i := 0; i < 9; i++ {
+		got = append(got, s.Sample(InfoLevel))
+	}
+	want := []bool{true, false, false, true, false, false, true, false, false}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("call %d: got %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestBasicSamplerZeroNDropsAll(t *testing.T) {
+	s := &BasicSampler{}
+	for i := 0; i < 5; i++ {
+		if s.Sample(InfoLevel) {
+			t.Fatalf("call %d: expected drop with N=0", i)
+		}
+	}
+}
+
+// TestBasicSamplerConcurrent proves Sample is safe for concurrent use, as
+// documented, and that exactly every Nth call across all goroutines is
+// accepted. Run with -race to catch data races on the counter.
+func TestBasicSamplerConcurrent(t *testing.T) {
+	const n = 4
+	const calls = 4000
+	s := &BasicSampler{N: n}
+
+	var accepted uint32
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < calls/8; i++ {
+				if s.Sample(InfoLevel) {
+					atomic.AddUint32(&accepted, 1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if want := uint32(calls / n); accepted != want {
+		t.Errorf("accepted = %d, want %d", accepted, want)
+	}
+}
+
+func TestLevelSamplerDispatchesPerLevel(t *testing.T) {
+	s := LevelSampler{
+		Debug: &BasicSampler{N: 0},
+		Info:  &BasicSampler{N: 1},
+	}
+	if s.Sample(DebugLevel) {
+		t.Error("Debug: expected drop")
+	}
+	if !s.Sample(InfoLevel) {
+		t.Error("Info: expected accept")
+	}
+	if !s.Sample(WarnLevel) {
+		t.Error("Warn: expected accept (no sampler configured)")
+	}
+	if !s.Sample(FatalLevel) {
+		t.Error("Fatal: expected accept (no sampler configured)")
+	}
+}
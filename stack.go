@@ -0,0 +1,128 @@
+package log
+
+import (
+	"reflect"
+	"runtime"
+	"strconv"
+)
+
+// errorStackTrace duck-types the "StackTrace() errors.StackTrace" method
+// errors from github.com/pkg/errors (and compatible packages) implement.
+// errors.StackTrace is a named slice of a named uintptr type, so a plain
+// `StackTrace() []uintptr` interface never matches it: Go requires
+// identical result types for interface satisfaction. Reflection lets us
+// accept any no-arg method named StackTrace that returns a slice whose
+// element's underlying kind is an unsigned integer, without depending on
+// the pkg/errors package itself.
+func errorStackTrace(err error) ([]uintptr, bool) {
+	if err == nil {
+		return nil, false
+	}
+	m := reflect.ValueOf(err).MethodByName("StackTrace")
+	if !m.IsValid() || m.Type().NumIn() != 0 || m.Type().NumOut() != 1 {
+		return nil, false
+	}
+	out := m.Type().Out(0)
+	if out.Kind() != reflect.Slice {
+		return nil, false
+	}
+	switch out.Elem().Kind() {
+	case reflect.Uintptr, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+	default:
+		return nil, false
+	}
+	v := m.Call(nil)[0]
+	pc := make([]uintptr, v.Len())
+	for i := range pc {
+		pc[i] = uintptr(v.Index(i).Uint())
+	}
+	return pc, true
+}
+
+// defaultStackMarshaler renders pc, as captured by runtime.Callers, as a
+// JSON array of {"func","file","line"} objects, resolving each frame
+// lazily via runtime.CallersFrames.
+func defaultStackMarshaler(pc []uintptr) []byte {
+	buf := make([]byte, 0, 64*len(pc))
+	buf = append(buf, '[')
+	frames := runtime.CallersFrames(pc)
+	first := true
+	for {
+		frame, more := frames.Next()
+		if !first {
+			buf = append(buf, ',')
+		}
+		first = false
+		buf = append(buf, `{"func":`...)
+		buf = strconv.AppendQuote(buf, frame.Function)
+		buf = append(buf, `,"file":`...)
+		buf = strconv.AppendQuote(buf, frame.File)
+		buf = append(buf, `,"line":`...)
+		buf = strconv.AppendInt(buf, int64(frame.Line), 10)
+		buf = append(buf, '}')
+		if !more {
+			break
+		}
+	}
+	buf = append(buf, ']')
+	return buf
+}
+
+// Stack captures the current call stack, skipping log-package frames,
+// and emits it as a "stack" field.
+func (e *Event) Stack() *Event {
+	if e == nil {
+		return nil
+	}
+	pc := make([]uintptr, 32)
+	n := runtime.Callers(2, pc)
+	return e.appendStack(pc[:n])
+}
+
+// StackErr emits a "stack" field from err's own stack trace if it
+// implements the StackTrace() method github.com/pkg/errors errors do,
+// falling back to a fresh capture at the call site otherwise.
+func (e *Event) StackErr(err error) *Event {
+	if e == nil {
+		return nil
+	}
+	if pc, ok := errorStackTrace(err); ok {
+		return e.appendStack(pc)
+	}
+	pc := make([]uintptr, 32)
+	n := runtime.Callers(2, pc)
+	return e.appendStack(pc[:n])
+}
+
+func (e *Event) appendStack(pc []uintptr) *Event {
+	if e.format == FormatCBOR {
+		e.cborKey("stack")
+		frames := runtime.CallersFrames(pc)
+		resolved := make([]runtime.Frame, 0, len(pc))
+		for {
+			frame, more := frames.Next()
+			resolved = append(resolved, frame)
+			if !more {
+				break
+			}
+		}
+		e.cborArrayHeader(len(resolved))
+		for _, frame := range resolved {
+			e.buf = appendCBORHead(e.buf, cborMajorMap, 3)
+			e.cborStr("func")
+			e.cborStr(frame.Function)
+			e.cborStr("file")
+			e.cborStr(frame.File)
+			e.cborStr("line")
+			e.cborInt64(int64(frame.Line))
+		}
+		return e
+	}
+	marshaler := e.stackMarshaler
+	if marshaler == nil {
+		marshaler = defaultStackMarshaler
+	}
+	e.key(',', "stack")
+	e.buf = append(e.buf, marshaler(pc)...)
+	return e
+}
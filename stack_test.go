@@ -0,0 +1,60 @@
+package log
+
+import "testing"
+
+// fakeFrame and fakeStackTrace faithfully mirror the shapes
+// github.com/pkg/errors uses (a named uintptr element type, in a named
+// slice type), without importing that package.
+type fakeFrame uintptr
+type fakeStackTrace []fakeFrame
+
+type fakeStackErr struct {
+	msg   string
+	trace fakeStackTrace
+}
+
+func (e *fakeStackErr) Error() string              { return e.msg }
+func (e *fakeStackErr) StackTrace() fakeStackTrace { return e.trace }
+
+func TestErrorStackTracePkgErrorsShape(t *testing.T) {
+	want := fakeStackTrace{0x1111, 0x2222, 0x3333}
+	err := &fakeStackErr{msg: "boom", trace: want}
+
+	pc, ok := errorStackTrace(err)
+	if !ok {
+		t.Fatal("errorStackTrace: expected pkg/errors-shaped StackTrace to be detected")
+	}
+	if len(pc) != len(want) {
+		t.Fatalf("got %d frames, want %d", len(pc), len(want))
+	}
+	for i := range want {
+		if pc[i] != uintptr(want[i]) {
+			t.Errorf("frame %d = %#x, want %#x", i, pc[i], want[i])
+		}
+	}
+}
+
+func TestErrorStackTraceNoMethod(t *testing.T) {
+	if _, ok := errorStackTrace(errPlain("boom")); ok {
+		t.Fatal("errorStackTrace: expected false for an error with no StackTrace method")
+	}
+}
+
+func TestErrorStackTraceNilError(t *testing.T) {
+	if _, ok := errorStackTrace(nil); ok {
+		t.Fatal("errorStackTrace: expected false for a nil error")
+	}
+}
+
+// TestStackErrNilErrorDoesNotPanic proves StackErr falls back to a fresh
+// capture instead of panicking when called with a nil error, since
+// reflect.ValueOf(nil).MethodByName would otherwise be called on a zero
+// Value.
+func TestStackErrNilErrorDoesNotPanic(t *testing.T) {
+	e := &Event{}
+	e.StackErr(nil)
+}
+
+type errPlain string
+
+func (e errPlain) Error() string { return string(e) }